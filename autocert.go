@@ -0,0 +1,63 @@
+package servhttp
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// AutocertOptions customizes the autocert.Manager used by ServeAutoCert. The
+// zero value falls back to a DirCache rooted at "golang-autocert" and a
+// HostPolicy built from the domains passed to ServeAutoCert, matching the
+// previous hard-coded behaviour.
+type AutocertOptions struct {
+	Cache      autocert.Cache
+	Email      string
+	HostPolicy func(ctx context.Context, host string) error
+}
+
+// NewDirCache returns a filesystem-backed autocert.Cache rooted at path.
+func NewDirCache(path string) autocert.Cache {
+	return autocert.DirCache(path)
+}
+
+// NewMemoryCache returns an in-memory autocert.Cache. Certificates are lost on
+// restart, which suits ephemeral containers, tests, and multi-instance
+// deployments that don't share a filesystem.
+func NewMemoryCache() autocert.Cache {
+	return &memoryCache{store: make(map[string][]byte)}
+}
+
+type memoryCache struct {
+	mu    sync.Mutex
+	store map[string][]byte
+}
+
+func (c *memoryCache) Get(_ context.Context, key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	data, ok := c.store[key]
+	if !ok {
+		return nil, autocert.ErrCacheMiss
+	}
+
+	return data, nil
+}
+
+func (c *memoryCache) Put(_ context.Context, key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.store[key] = data
+	return nil
+}
+
+func (c *memoryCache) Delete(_ context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.store, key)
+	return nil
+}
@@ -0,0 +1,38 @@
+package servhttp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache()
+
+	if _, err := cache.Get(ctx, "example.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("got err = %v, want %v", err, autocert.ErrCacheMiss)
+	}
+
+	if err := cache.Put(ctx, "example.com", []byte("cert-bytes")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	data, err := cache.Get(ctx, "example.com")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(data) != "cert-bytes" {
+		t.Fatalf("got data = %q, want %q", data, "cert-bytes")
+	}
+
+	if err := cache.Delete(ctx, "example.com"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := cache.Get(ctx, "example.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("got err = %v after delete, want %v", err, autocert.ErrCacheMiss)
+	}
+}
@@ -0,0 +1,111 @@
+package servhttp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"runtime/debug"
+	"time"
+)
+
+// Middleware wraps an http.Handler with cross-cutting behaviour.
+type Middleware func(http.Handler) http.Handler
+
+// Use composes mw around the router in registration order, so the first
+// middleware ever passed to Use is the outermost and runs first. Order is
+// preserved across multiple Use calls: each call rebuilds the full chain
+// from scratch instead of wrapping the result of the previous call, which
+// would otherwise reverse the order between calls.
+func (s *ServHTTP) Use(mw ...Middleware) {
+	if s.baseHandler == nil {
+		s.baseHandler = s.Handler
+	}
+
+	s.middlewares = append(s.middlewares, mw...)
+
+	handler := s.baseHandler
+	for i := len(s.middlewares) - 1; i >= 0; i-- {
+		handler = s.middlewares[i](handler)
+	}
+	s.Handler = handler
+}
+
+type requestIDKey struct{}
+
+// RequestID injects an X-Request-ID into both the request context and the
+// response header, generating one when the client didn't supply it.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get("X-Request-ID")
+			if id == "" {
+				id = newRequestID()
+			}
+
+			w.Header().Set("X-Request-ID", id)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+		})
+	}
+}
+
+func newRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// responseWriter wraps http.ResponseWriter to capture the status code and
+// byte count written, for AccessLog.
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += n
+	return n, err
+}
+
+// AccessLog logs one line per request with method, path, status, bytes written
+// and duration, using the ServHTTP's own logger.
+func (s *ServHTTP) AccessLog() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			rw := &responseWriter{ResponseWriter: w}
+
+			next.ServeHTTP(rw, r)
+
+			s.Printf("%s %s %d %d %s", r.Method, r.URL.Path, rw.status, rw.bytes, time.Since(start))
+		})
+	}
+}
+
+// Recover converts panics raised downstream into a 500 response, logging the
+// stack trace instead of crashing the server.
+func (s *ServHTTP) Recover() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					s.Printf("panic: %v\n%s", rec, debug.Stack())
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package servhttp
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestUseOrdering(t *testing.T) {
+	var order []string
+
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	s := New(":0")
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+	s.Use(mark("first"), mark("second"))
+
+	s.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"first", "second", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("got order %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestUseOrderingAcrossMultipleCalls(t *testing.T) {
+	var order []string
+
+	mark := func(name string) Middleware {
+		return func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				order = append(order, name)
+				next.ServeHTTP(w, r)
+			})
+		}
+	}
+
+	s := New(":0")
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		order = append(order, "handler")
+	})
+	s.Use(mark("first"))
+	s.Use(mark("second"))
+
+	s.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	want := []string{"first", "second", "handler"}
+	if len(order) != len(want) {
+		t.Fatalf("got order %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("got order %v, want %v", order, want)
+		}
+	}
+}
+
+func TestRecoverGuardsMiddlewareRegisteredAfterIt(t *testing.T) {
+	s := New(":0")
+	s.Logger = log.New(&bytes.Buffer{}, "", 0)
+	s.AddHandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {})
+
+	s.Use(s.Recover())
+	s.AddAuthFunc(func(r *http.Request) bool {
+		panic("auth backend unreachable")
+	}, "/login")
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want %d (Recover, registered first, should still wrap AddAuthFunc)", rec.Code, http.StatusInternalServerError)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	s := New(":0")
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	s.Use(RequestID())
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Header().Get("X-Request-ID") == "" {
+		t.Fatal("got empty X-Request-ID header, want a generated id")
+	}
+}
+
+func TestRequestIDPreservesIncoming(t *testing.T) {
+	s := New(":0")
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	s.Use(RequestID())
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "incoming-id")
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, req)
+
+	if got := rec.Header().Get("X-Request-ID"); got != "incoming-id" {
+		t.Fatalf("got X-Request-ID = %q, want %q", got, "incoming-id")
+	}
+}
+
+func TestAccessLog(t *testing.T) {
+	var buf bytes.Buffer
+
+	s := New(":0")
+	s.Logger = log.New(&buf, "", 0)
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	})
+	s.Use(s.AccessLog())
+
+	s.Handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	got := buf.String()
+	if !bytes.Contains([]byte(got), []byte("GET / 418 15")) {
+		t.Fatalf("got log line %q, want it to contain %q", got, "GET / 418 15")
+	}
+}
+
+func TestRecover(t *testing.T) {
+	s := New(":0")
+	s.Logger = log.New(&bytes.Buffer{}, "", 0)
+	s.AddHandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	})
+	s.Use(s.Recover())
+
+	rec := httptest.NewRecorder()
+	s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusInternalServerError)
+	}
+}
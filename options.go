@@ -0,0 +1,106 @@
+package servhttp
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+)
+
+// Mode identifies which of the three ways Start runs the server.
+type Mode int
+
+const (
+	// ModeDev serves plain HTTP on Options.DevPort, for local development.
+	ModeDev Mode = iota
+	// ModeStandalone serves HTTP+HTTPS with Let's Encrypt autocert.
+	ModeStandalone
+	// ModeSlave serves plain HTTP on Options.Port behind a reverse proxy or PaaS
+	// (e.g. Heroku) that terminates TLS itself.
+	ModeSlave
+)
+
+// Options configures the run Mode Start selects and the listener settings for
+// that mode. Populate it by hand, or call LoadEnv to read it from the
+// environment in the style of a 12-factor app.
+type Options struct {
+	Host string
+	Port string
+
+	// DevPort, when set, selects ModeDev.
+	DevPort string
+
+	// LetsEncryptEnabled, when true and DevPort is unset, selects ModeStandalone.
+	LetsEncryptEnabled  bool
+	LetsEncryptCacheDir string
+	LetsEncryptEmail    string
+}
+
+// LoadEnv populates o from HOST, PORT, LETSENCRYPT_ENABLED, LETSENCRYPT_CACHE_DIR,
+// LETSENCRYPT_EMAIL and DEV_PORT.
+func (o *Options) LoadEnv() error {
+	o.Host = os.Getenv("HOST")
+	o.Port = os.Getenv("PORT")
+	o.DevPort = os.Getenv("DEV_PORT")
+	o.LetsEncryptCacheDir = os.Getenv("LETSENCRYPT_CACHE_DIR")
+	o.LetsEncryptEmail = os.Getenv("LETSENCRYPT_EMAIL")
+
+	if v := os.Getenv("LETSENCRYPT_ENABLED"); v != "" {
+		enabled, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("parse LETSENCRYPT_ENABLED: %s", err)
+		}
+		o.LetsEncryptEnabled = enabled
+	}
+
+	return nil
+}
+
+// Mode reports the run mode implied by o: dev when DevPort is set, standalone
+// when Let's Encrypt is enabled, otherwise slave mode behind a reverse proxy.
+func (o *Options) Mode() Mode {
+	switch {
+	case o.DevPort != "":
+		return ModeDev
+	case o.LetsEncryptEnabled:
+		return ModeStandalone
+	default:
+		return ModeSlave
+	}
+}
+
+// Start builds a ServHTTP for handler according to opts' Mode and begins
+// serving it in the background, returning the running server so callers get
+// a 12-factor-friendly server in one call without hand-wiring ServeAutoCert.
+func Start(handler http.Handler, opts Options) *ServHTTP {
+	var s *ServHTTP
+
+	switch opts.Mode() {
+	case ModeDev:
+		s = New(":" + opts.DevPort)
+		s.Handler = handler
+		go s.ServeAndShutdown()
+	case ModeStandalone:
+		s = New(":https")
+		s.Handler = handler
+		s.Autocert.Email = opts.LetsEncryptEmail
+		if opts.LetsEncryptCacheDir != "" {
+			s.Autocert.Cache = NewDirCache(opts.LetsEncryptCacheDir)
+		}
+		if opts.Host != "" {
+			go s.ServeAndShutdown(opts.Host)
+		} else {
+			// No HOST configured: ServeAutoCert would otherwise receive a
+			// single empty domain and install a HostWhitelist("") that
+			// rejects every real hostname. Fall back to plain HTTP instead
+			// of shipping a server that can never issue a cert.
+			go s.ServeAndShutdown()
+		}
+	default: // ModeSlave
+		s = New(":" + opts.Port)
+		s.Handler = handler
+		go s.ServeAndShutdown()
+	}
+
+	return s
+}
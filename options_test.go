@@ -0,0 +1,79 @@
+package servhttp
+
+import "testing"
+
+func TestOptionsMode(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		want Mode
+	}{
+		{
+			name: "dev port set selects ModeDev",
+			opts: Options{DevPort: "8080"},
+			want: ModeDev,
+		},
+		{
+			name: "lets encrypt enabled selects ModeStandalone",
+			opts: Options{LetsEncryptEnabled: true},
+			want: ModeStandalone,
+		},
+		{
+			name: "dev port takes priority over lets encrypt",
+			opts: Options{DevPort: "8080", LetsEncryptEnabled: true},
+			want: ModeDev,
+		},
+		{
+			name: "neither set selects ModeSlave",
+			opts: Options{Port: "3000"},
+			want: ModeSlave,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.Mode(); got != tt.want {
+				t.Fatalf("got Mode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOptionsLoadEnv(t *testing.T) {
+	t.Setenv("HOST", "example.com")
+	t.Setenv("PORT", "3000")
+	t.Setenv("DEV_PORT", "")
+	t.Setenv("LETSENCRYPT_ENABLED", "true")
+	t.Setenv("LETSENCRYPT_CACHE_DIR", "/var/cache/autocert")
+	t.Setenv("LETSENCRYPT_EMAIL", "ops@example.com")
+
+	var o Options
+	if err := o.LoadEnv(); err != nil {
+		t.Fatalf("LoadEnv() error = %v", err)
+	}
+
+	if o.Host != "example.com" {
+		t.Errorf("got Host = %q, want %q", o.Host, "example.com")
+	}
+	if o.Port != "3000" {
+		t.Errorf("got Port = %q, want %q", o.Port, "3000")
+	}
+	if !o.LetsEncryptEnabled {
+		t.Error("got LetsEncryptEnabled = false, want true")
+	}
+	if o.LetsEncryptCacheDir != "/var/cache/autocert" {
+		t.Errorf("got LetsEncryptCacheDir = %q, want %q", o.LetsEncryptCacheDir, "/var/cache/autocert")
+	}
+	if o.LetsEncryptEmail != "ops@example.com" {
+		t.Errorf("got LetsEncryptEmail = %q, want %q", o.LetsEncryptEmail, "ops@example.com")
+	}
+}
+
+func TestOptionsLoadEnvInvalidBool(t *testing.T) {
+	t.Setenv("LETSENCRYPT_ENABLED", "not-a-bool")
+
+	var o Options
+	if err := o.LoadEnv(); err == nil {
+		t.Fatal("LoadEnv() error = nil, want non-nil for an unparsable LETSENCRYPT_ENABLED")
+	}
+}
@@ -0,0 +1,124 @@
+package servhttp
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// AddRequestTimeout wraps the handler with a per-request deadline, separate from
+// ReadTimeout/WriteTimeout (which fire at the socket layer and can't be observed
+// by handlers). If the handler hasn't returned by the time timeout elapses, the
+// client gets a 503 with a "Connection: close" header so it tears down keep-alive,
+// and the request's context is canceled so downstream work can abort. skip, when
+// non-nil, lets routes such as long-polling or SSE opt out of the deadline.
+//
+// The handler runs in its own goroutine against a private buffering
+// ResponseWriter, the same approach net/http's own TimeoutHandler uses, so a
+// handler that's still running after the deadline can never write to the real
+// ResponseWriter concurrently with (or after) the timeout response.
+func (s *ServHTTP) AddRequestTimeout(timeout time.Duration, skip func(r *http.Request) bool) {
+	handler := s.Handler
+	s.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if skip != nil && skip(r) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+
+		tw := newTimeoutWriter()
+
+		done := make(chan struct{})
+		go func() {
+			handler.ServeHTTP(tw, r.WithContext(ctx))
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			tw.copyTo(w)
+		case <-ctx.Done():
+			tw.discard()
+			w.Header().Set("Connection", "close")
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+}
+
+// timeoutWriter buffers a handler's response so it can be discarded, instead
+// of written to the real ResponseWriter, if the request times out first.
+type timeoutWriter struct {
+	mu          sync.Mutex
+	header      http.Header
+	buf         bytes.Buffer
+	code        int
+	wroteHeader bool
+	discarded   bool
+}
+
+func newTimeoutWriter() *timeoutWriter {
+	return &timeoutWriter{header: make(http.Header)}
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.wroteHeader {
+		return
+	}
+
+	tw.code = code
+	tw.wroteHeader = true
+}
+
+func (tw *timeoutWriter) Write(p []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	if tw.discarded {
+		return len(p), nil
+	}
+
+	if !tw.wroteHeader {
+		tw.code = http.StatusOK
+		tw.wroteHeader = true
+	}
+
+	return tw.buf.Write(p)
+}
+
+// copyTo replays the buffered response onto w. Called once the handler has
+// returned within the deadline.
+func (tw *timeoutWriter) copyTo(w http.ResponseWriter) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	for k, v := range tw.header {
+		w.Header()[k] = v
+	}
+
+	if !tw.wroteHeader {
+		tw.code = http.StatusOK
+	}
+
+	w.WriteHeader(tw.code)
+	_, _ = w.Write(tw.buf.Bytes())
+}
+
+// discard marks the writer so any further handler writes, after the deadline
+// has already fired, are dropped instead of buffered.
+func (tw *timeoutWriter) discard() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+
+	tw.discarded = true
+}
@@ -0,0 +1,64 @@
+package servhttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAddRequestTimeout(t *testing.T) {
+	t.Run("returns 503 and closes the connection when the handler is too slow", func(t *testing.T) {
+		s := New(":0")
+		s.AddHandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+			<-r.Context().Done()
+		})
+		s.AddRequestTimeout(10*time.Millisecond, nil)
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+		if got := rec.Header().Get("Connection"); got != "close" {
+			t.Fatalf("got Connection header %q, want %q", got, "close")
+		}
+	})
+
+	t.Run("passes through a handler that finishes in time", func(t *testing.T) {
+		s := New(":0")
+		s.AddHandleFunc("/fast", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte("ok"))
+		})
+		s.AddRequestTimeout(time.Second, nil)
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+		if rec.Body.String() != "ok" {
+			t.Fatalf("got body %q, want %q", rec.Body.String(), "ok")
+		}
+	})
+
+	t.Run("skip bypasses the deadline entirely", func(t *testing.T) {
+		s := New(":0")
+		s.AddHandleFunc("/events", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		})
+		s.AddRequestTimeout(time.Nanosecond, func(r *http.Request) bool {
+			return r.URL.Path == "/events"
+		})
+
+		rec := httptest.NewRecorder()
+		s.Handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/events", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+}
@@ -5,6 +5,7 @@ package servhttp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"time"
 
 	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/sync/errgroup"
 )
 
 // HTTP server type with a logger.
@@ -24,10 +26,22 @@ type ServHTTP struct {
 	// Server stop channel.
 	Stop chan error
 
-	authFunc        func(r *http.Request) bool
-	redirectUrl     string
 	router          *http.ServeMux
 	timeoutShutdown time.Duration
+
+	// httpServer serves ACME HTTP-01 challenges on :http and redirects
+	// everything else to HTTPS. Only set once ServeAutoCert is running.
+	httpServer *http.Server
+
+	// middlewares and baseHandler back Use: baseHandler is whatever Handler
+	// was set before the first Use call, and middlewares holds every
+	// registration across all Use calls so the full chain can be rebuilt in
+	// call order each time.
+	middlewares []Middleware
+	baseHandler http.Handler
+
+	// Autocert customizes the autocert.Manager used by ServeAutoCert.
+	Autocert AutocertOptions
 }
 
 // This function creates a new HTTP server with an empty handler.
@@ -63,14 +77,15 @@ func (s *ServHTTP) AddHandleFunc(pattern string, handler func(http.ResponseWrite
 
 // AddAuthFunc adds middleware authentication.
 func (s *ServHTTP) AddAuthFunc(f func(r *http.Request) bool, redirectUrl string) {
-	handler := s.Handler
-	s.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.URL.Path != redirectUrl && !f(r) {
-			http.Redirect(w, r, redirectUrl, http.StatusTemporaryRedirect)
-			return
-		}
-		// Assuming authentication passed, run the original handler
-		handler.ServeHTTP(w, r)
+	s.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != redirectUrl && !f(r) {
+				http.Redirect(w, r, redirectUrl, http.StatusTemporaryRedirect)
+				return
+			}
+			// Assuming authentication passed, run the original handler
+			next.ServeHTTP(w, r)
+		})
 	})
 }
 
@@ -90,15 +105,23 @@ func (s *ServHTTP) Config(logger *log.Logger, timeout time.Duration) {
 	}
 }
 
-// Graceful shutdown method.
-func (s *ServHTTP) Shutdown() error {
-	quit := make(chan os.Signal)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+// Run starts serving via ServeAutoCert and blocks until ctx is canceled or a
+// listener fails. When ctx is canceled it gracefully stops every running
+// server within timeoutShutdown; a listener failure is returned as an error
+// straight away. Because the lifecycle is driven by ctx, callers can compose
+// their own cancellation (signals, a parent ctx, other services in the same
+// process) instead of relying on built-in signal handling.
+func (s *ServHTTP) Run(ctx context.Context, domains ...string) error {
+	go func() {
+		s.Stop <- s.ServeAutoCert(domains...)
+	}()
+
+	s.Println("server started")
 
 	select {
 	case err := <-s.Stop:
 		return fmt.Errorf("listen: %v\n", err)
-	case <-quit:
+	case <-ctx.Done():
 	}
 
 	s.Println("server shutdown ...")
@@ -106,8 +129,22 @@ func (s *ServHTTP) Shutdown() error {
 	ctxTimeout, cancelTimeout := context.WithTimeout(context.Background(), s.timeoutShutdown)
 	defer cancelTimeout()
 
+	// Shut both servers down unconditionally: an error from one must not
+	// prevent the other's listener from being stopped.
+	var errs []error
+
 	if err := s.Server.Shutdown(ctxTimeout); err != nil {
-		return fmt.Errorf("server shutdown: %s", err)
+		errs = append(errs, fmt.Errorf("server shutdown: %s", err))
+	}
+
+	if s.httpServer != nil {
+		if err := s.httpServer.Shutdown(ctxTimeout); err != nil {
+			errs = append(errs, fmt.Errorf("redirect server shutdown: %s", err))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
 	}
 
 	s.Println("server shut down")
@@ -115,32 +152,89 @@ func (s *ServHTTP) Shutdown() error {
 }
 
 // ServeAutoCert runs http.ListenAndServe if the domains slice is empty.
-// Otherwise, it runs http.ListenAndServeTLS with a list of domains using Let's Encrypt.
+// Otherwise, it runs http.ListenAndServeTLS with a list of domains using Let's Encrypt,
+// alongside a companion HTTP server on :http that answers ACME HTTP-01 challenges
+// and redirects everything else to HTTPS. Both listeners run under an errgroup, so
+// either one failing tears down the other. s.Autocert customizes the manager's
+// cache, ACME account email and host policy; an unset Cache or HostPolicy falls
+// back to a local DirCache and a whitelist built from domains.
 func (s *ServHTTP) ServeAutoCert(domains ...string) error {
 	if len(domains) == 0 {
 		return s.ListenAndServe()
 	}
 
+	cache := s.Autocert.Cache
+	if cache == nil {
+		cache = autocert.DirCache("golang-autocert")
+	}
+
+	hostPolicy := s.Autocert.HostPolicy
+	if hostPolicy == nil {
+		hostPolicy = autocert.HostWhitelist(domains...)
+	}
+
 	m := &autocert.Manager{
-		Cache:      autocert.DirCache("golang-autocert"),
+		Cache:      cache,
 		Prompt:     autocert.AcceptTOS,
-		HostPolicy: autocert.HostWhitelist(domains...),
+		Email:      s.Autocert.Email,
+		HostPolicy: hostPolicy,
 	}
 
 	s.Server.TLSConfig = m.TLSConfig()
 
-	return s.ListenAndServeTLS("", "")
+	s.httpServer = &http.Server{
+		Addr:         ":http",
+		Handler:      m.HTTPHandler(nil),
+		ErrorLog:     s.Logger,
+		ReadTimeout:  s.Server.ReadTimeout,
+		WriteTimeout: s.Server.WriteTimeout,
+		IdleTimeout:  s.Server.IdleTimeout,
+	}
+
+	// ctx is canceled as soon as either listener below returns, for any
+	// reason. http.Server doesn't watch a context on its own, so without the
+	// teardown goroutine racing against ctx.Done(), one listener failing
+	// would leave the other blocking in ListenAndServe forever instead of
+	// tearing the whole thing down.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var g errgroup.Group
+
+	g.Go(func() error {
+		defer cancel()
+		if err := s.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		defer cancel()
+		if err := s.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	})
+
+	g.Go(func() error {
+		<-ctx.Done()
+		_ = s.Server.Shutdown(context.Background())
+		_ = s.httpServer.Shutdown(context.Background())
+		return nil
+	})
+
+	return g.Wait()
 }
 
-// This method combines the methods of ServeAutoCert and Shutdown.
+// ServeAndShutdown is a thin, backwards-compatible wrapper around Run: it
+// installs signal.NotifyContext for SIGINT/SIGTERM and runs until one of them
+// arrives or the listener fails.
 func (s *ServHTTP) ServeAndShutdown(domains ...string) {
-	go func() {
-		s.Stop <- s.ServeAutoCert(domains...)
-	}()
-
-	s.Println("server started")
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
 
-	if err := s.Shutdown(); err != nil {
+	if err := s.Run(ctx, domains...); err != nil {
 		s.Fatalln(err)
 	}
 }
@@ -0,0 +1,33 @@
+package servhttp
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestServeAutoCertTearsDownOnListenerFailure(t *testing.T) {
+	// Occupy :http ourselves so the redirect server's ListenAndServe fails
+	// immediately on bind, simulating one listener failing outright.
+	blocker, err := net.Listen("tcp", ":http")
+	if err != nil {
+		t.Skipf("could not bind :http to simulate a collision: %v", err)
+	}
+	defer blocker.Close()
+
+	s := New("127.0.0.1:0")
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.ServeAutoCert("example.com")
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Fatal("ServeAutoCert() error = nil, want a bind error from the redirect listener")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("ServeAutoCert did not return within 2s: the TLS listener was not torn down after the redirect listener failed")
+	}
+}